@@ -0,0 +1,18 @@
+package config
+
+// GitServerType identifies which git forge backend newGitServer should build to manage
+// Zarf's in-cluster git state (orgs, users, quotas, collaborators).
+type GitServerType string
+
+const (
+	// GitServerGitea targets the Gitea instance Zarf bundles by default
+	GitServerGitea GitServerType = "gitea"
+	// GitServerGogs targets an existing Gogs instance instead of the bundled Gitea
+	GitServerGogs GitServerType = "gogs"
+	// GitServerGitLab targets an existing GitLab instance instead of the bundled Gitea
+	GitServerGitLab GitServerType = "gitlab"
+)
+
+// GitServerProvider selects which GitServerType backend Zarf's git admin operations talk to.
+// It defaults to the bundled Gitea and is set by the deploying package's zarf-config.
+var GitServerProvider = GitServerGitea
@@ -0,0 +1,160 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	netHttp "net/http"
+	"strconv"
+	"time"
+
+	"github.com/defenseunicorns/zarf/cli/internal/message"
+)
+
+// defaultGiteaDeadline bounds how long giteaClient will keep retrying a single call,
+// long enough to ride out a cold-starting Gitea pod without hanging forever
+const defaultGiteaDeadline = time.Minute
+
+// giteaErrorKind classifies a failed Gitea API call so callers can tell an auth failure
+// apart from a transient network blip or a validation error
+type giteaErrorKind string
+
+const (
+	giteaErrorAuth       giteaErrorKind = "auth"
+	giteaErrorTransient  giteaErrorKind = "transient"
+	giteaErrorValidation giteaErrorKind = "validation"
+)
+
+// GiteaError wraps a failed Gitea API response with enough detail for a caller to decide
+// whether to retry, surface the failure to the user, or treat it as a bug
+type GiteaError struct {
+	Kind       giteaErrorKind
+	StatusCode int
+	Body       string
+}
+
+func (e *GiteaError) Error() string {
+	return fmt.Sprintf("gitea request failed with a %s error (status %d): %s", e.Kind, e.StatusCode, e.Body)
+}
+
+// IsGiteaAuthError reports whether err is a GiteaError caused by an auth failure (401/403)
+func IsGiteaAuthError(err error) bool {
+	var giteaErr *GiteaError
+	return errors.As(err, &giteaErr) && giteaErr.Kind == giteaErrorAuth
+}
+
+// giteaClient issues Gitea admin API requests with exponential backoff and treats the
+// "already exists" responses Gitea returns for a repeat create (409/422) as success, so
+// callers like CreateZarfOrg/CreateReadOnlyUser/addReadOnlyUser are safe to re-run against
+// a Gitea pod that is still coming up or that already has the state they're asking for.
+type giteaClient struct {
+	httpClient *netHttp.Client
+	baseURL    string
+	username   string
+	password   string
+	deadline   time.Duration
+}
+
+// newGiteaClient builds a giteaClient for baseURL, authenticating as username/password
+func newGiteaClient(baseURL string, username string, password string) *giteaClient {
+	return &giteaClient{
+		httpClient: &netHttp.Client{Timeout: 10 * time.Second},
+		baseURL:    baseURL,
+		username:   username,
+		password:   password,
+		deadline:   defaultGiteaDeadline,
+	}
+}
+
+// idempotentCreate/notIdempotentCreate make giteaClient.do call sites self-documenting
+const (
+	idempotentCreate    = true
+	notIdempotentCreate = false
+)
+
+// do issues method/path with the given JSON body, retrying transient failures (connection
+// errors and 5xx responses) with exponential backoff until c.deadline elapses. A 429 or 5xx
+// response's Retry-After header, when present, overrides the backoff delay. When
+// tolerateConflict is true (create calls pass idempotentCreate), a 409 or 422 response is
+// treated as an already-exists success rather than an error; edit calls pass
+// notIdempotentCreate so a validation failure there (e.g. a
+// rejected quota change) is still surfaced as an error instead of silently "succeeding".
+func (c *giteaClient) do(method string, path string, body interface{}, tolerateConflict bool) ([]byte, error) {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	deadlineAt := time.Now().Add(c.deadline)
+	backoff := 250 * time.Millisecond
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		request, err := netHttp.NewRequest(method, c.baseURL+path, bytes.NewReader(reqBody))
+		if err != nil {
+			return nil, err
+		}
+		request.SetBasicAuth(c.username, c.password)
+		request.Header.Set("accept", "application/json")
+		request.Header.Set("Content-Type", "application/json")
+
+		response, err := c.httpClient.Do(request)
+		if err != nil {
+			lastErr = err
+			message.Debugf("gitea %s %s failed (attempt %d): %v", method, path, attempt, err)
+		} else {
+			responseBody, readErr := io.ReadAll(response.Body)
+			response.Body.Close()
+			if readErr != nil {
+				return nil, readErr
+			}
+
+			switch {
+			case response.StatusCode >= 200 && response.StatusCode < 300:
+				return responseBody, nil
+			case tolerateConflict && (response.StatusCode == netHttp.StatusConflict || response.StatusCode == netHttp.StatusUnprocessableEntity):
+				message.Debugf("gitea %s %s already satisfied (status %s), treating as success", method, path, response.Status)
+				return responseBody, nil
+			case response.StatusCode == netHttp.StatusUnauthorized || response.StatusCode == netHttp.StatusForbidden:
+				return nil, &GiteaError{Kind: giteaErrorAuth, StatusCode: response.StatusCode, Body: string(responseBody)}
+			case response.StatusCode == netHttp.StatusTooManyRequests:
+				lastErr = &GiteaError{Kind: giteaErrorTransient, StatusCode: response.StatusCode, Body: string(responseBody)}
+				if retryAfter, ok := parseRetryAfter(response.Header.Get("Retry-After")); ok {
+					backoff = retryAfter
+				}
+			case response.StatusCode >= 400 && response.StatusCode < 500:
+				return nil, &GiteaError{Kind: giteaErrorValidation, StatusCode: response.StatusCode, Body: string(responseBody)}
+			default:
+				lastErr = &GiteaError{Kind: giteaErrorTransient, StatusCode: response.StatusCode, Body: string(responseBody)}
+				if retryAfter, ok := parseRetryAfter(response.Header.Get("Retry-After")); ok {
+					backoff = retryAfter
+				}
+			}
+		}
+
+		if time.Now().Add(backoff).After(deadlineAt) {
+			return nil, fmt.Errorf("gitea %s %s did not succeed within %s: %w", method, path, c.deadline, lastErr)
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// parseRetryAfter parses a Retry-After header's delay-seconds form into a duration
+func parseRetryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,115 @@
+package git
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestGiteaClientRetriesUntilColdStartPodComesUp simulates a Gitea pod that answers its
+// first two requests with 503 Service Unavailable, as happens while it's still starting up
+func TestGiteaClientRetriesUntilColdStartPodComesUp(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	client := newGiteaClient(server.URL, "zarf-git-user", "secret")
+	client.deadline = 5 * time.Second
+
+	if _, err := client.do("POST", "/api/v1/orgs", map[string]string{"username": "zarf"}, idempotentCreate); err != nil {
+		t.Fatalf("expected the client to retry through the cold start, got error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("expected exactly 3 calls, got %d", got)
+	}
+}
+
+// TestGiteaClientTreatsAlreadyExistsAsSuccess ensures a double-run of a create call
+// against a Gitea that already has the org/user/collaborator doesn't surface as an error
+func TestGiteaClientTreatsAlreadyExistsAsSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"message":"already exists"}`))
+	}))
+	defer server.Close()
+
+	client := newGiteaClient(server.URL, "zarf-git-user", "secret")
+
+	if _, err := client.do("POST", "/api/v1/orgs", map[string]string{"username": "zarf"}, idempotentCreate); err != nil {
+		t.Errorf("expected a 409 to be treated as an idempotent success on a create call, got error: %v", err)
+	}
+}
+
+// TestGiteaClientSurfacesConflictOnEditCalls ensures a 409/422 on an edit call (e.g. the
+// quota PATCH, which is not a create) is never swallowed as success
+func TestGiteaClientSurfacesConflictOnEditCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message":"validation failed"}`))
+	}))
+	defer server.Close()
+
+	client := newGiteaClient(server.URL, "zarf-git-user", "secret")
+
+	_, err := client.do("PATCH", "/api/v1/admin/users/zarf-reader", map[string]string{"email": "zarf-reader@localhost.local"}, notIdempotentCreate)
+	if err == nil {
+		t.Fatal("expected a 422 on an edit call to surface as an error")
+	}
+}
+
+// TestGiteaClientSurfacesAuthErrors ensures a 401/403 is not retried and is classified as an auth error
+func TestGiteaClientSurfacesAuthErrors(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := newGiteaClient(server.URL, "zarf-git-user", "wrong-secret")
+
+	_, err := client.do("POST", "/api/v1/orgs", map[string]string{"username": "zarf"}, idempotentCreate)
+	if !IsGiteaAuthError(err) {
+		t.Fatalf("expected an auth error, got: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("expected auth failures not to be retried, got %d calls", got)
+	}
+}
+
+// TestGiteaClientHonorsRetryAfter ensures a Retry-After header overrides the default backoff
+func TestGiteaClientHonorsRetryAfter(t *testing.T) {
+	var calls int32
+	start := time.Now()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newGiteaClient(server.URL, "zarf-git-user", "secret")
+
+	if _, err := client.do("GET", "/api/v1/orgs", nil, idempotentCreate); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected the client to wait for the Retry-After delay, only waited %s", elapsed)
+	}
+}
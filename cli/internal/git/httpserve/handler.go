@@ -0,0 +1,216 @@
+// Package httpserve implements a smart-HTTP git server for mirrored repos, as a lightweight
+// in-cluster fallback when running the full Gitea pod is undesirable.
+package httpserve
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	netHttp "net/http"
+	"net/textproto"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/cli/internal/git"
+	"github.com/defenseunicorns/zarf/cli/internal/message"
+)
+
+// Authenticator validates the basic-auth credentials on an incoming git request
+type Authenticator func(username string, password string) bool
+
+// Handler serves the git smart-HTTP protocol (info/refs, git-upload-pack, git-receive-pack)
+// for the bare repos under RepoRoot by shelling out to `git http-backend`.
+type Handler struct {
+	// RepoRoot is the directory containing the bare mirrored repos, as laid out by git.MirrorClone
+	RepoRoot string
+	// Authenticate validates the credentials produced by CreateReadOnlyUser/ZarfGitPushUser.
+	// A nil Authenticate allows every request through.
+	Authenticate Authenticator
+	// MutateHost, if non-empty, rewrites absolute git URLs found in text responses via
+	// git.MutateGitUrlsInText so redirected clones from outside the cluster keep working.
+	MutateHost string
+}
+
+// NewHandler builds a Handler serving the bare repos under repoRoot
+func NewHandler(repoRoot string, authenticate Authenticator, mutateHost string) *Handler {
+	return &Handler{RepoRoot: repoRoot, Authenticate: authenticate, MutateHost: mutateHost}
+}
+
+// ServeHTTP implements net/http.Handler
+func (h *Handler) ServeHTTP(w netHttp.ResponseWriter, r *netHttp.Request) {
+	if !h.authenticate(w, r) {
+		return
+	}
+
+	switch {
+	case r.Method == netHttp.MethodGet && strings.HasSuffix(r.URL.Path, "/info/refs"):
+		h.serveBackend(w, r, r.URL.Query().Get("service"), true)
+	case r.Method == netHttp.MethodPost && strings.HasSuffix(r.URL.Path, "/git-upload-pack"):
+		h.serveBackend(w, r, "git-upload-pack", false)
+	case r.Method == netHttp.MethodPost && strings.HasSuffix(r.URL.Path, "/git-receive-pack"):
+		h.serveBackend(w, r, "git-receive-pack", false)
+	default:
+		netHttp.NotFound(w, r)
+	}
+}
+
+// authenticate challenges the request for basic-auth credentials, writing the 401 response
+// itself when they're missing or invalid. It returns whether the request may proceed.
+func (h *Handler) authenticate(w netHttp.ResponseWriter, r *netHttp.Request) bool {
+	if h.Authenticate == nil {
+		return true
+	}
+
+	username, password, ok := r.BasicAuth()
+	if !ok || !h.Authenticate(username, password) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="."`)
+		netHttp.Error(w, "unauthorized", netHttp.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// requestBody returns the request body along with its true length, gzip-decoding it first
+// when the client sent Content-Encoding: gzip. The length can't be known up front for a
+// gzip-decoded body, so it's buffered in full rather than streamed — callers need the real
+// (decompressed) length to hand `git http-backend` a correct CONTENT_LENGTH.
+func requestBody(r *netHttp.Request) (io.ReadCloser, int64, error) {
+	if !strings.EqualFold(r.Header.Get("Content-Encoding"), "gzip") {
+		return r.Body, r.ContentLength, nil
+	}
+
+	gzipReader, err := gzip.NewReader(r.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer gzipReader.Close()
+
+	decoded, err := io.ReadAll(gzipReader)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return io.NopCloser(bytes.NewReader(decoded)), int64(len(decoded)), nil
+}
+
+// serveBackend runs `git http-backend` as a CGI process for service (git-upload-pack or
+// git-receive-pack) and streams its response back to w. advertisement indicates this is the
+// GET info/refs capability advertisement rather than a POST pack exchange.
+func (h *Handler) serveBackend(w netHttp.ResponseWriter, r *netHttp.Request, service string, advertisement bool) {
+	if service != "git-upload-pack" && service != "git-receive-pack" {
+		netHttp.Error(w, "unknown service", netHttp.StatusBadRequest)
+		return
+	}
+
+	body, contentLength, err := requestBody(r)
+	if err != nil {
+		netHttp.Error(w, "invalid gzip body", netHttp.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+
+	cmd := exec.Command("git", "http-backend")
+	cmd.Dir = h.RepoRoot
+	cmd.Env = append(os.Environ(),
+		"GIT_PROJECT_ROOT="+h.RepoRoot,
+		"GIT_HTTP_EXPORT_ALL=1",
+		"REQUEST_METHOD="+r.Method,
+		"QUERY_STRING="+r.URL.RawQuery,
+		"PATH_INFO="+r.URL.Path,
+		"CONTENT_TYPE="+r.Header.Get("Content-Type"),
+	)
+	if username, _, ok := r.BasicAuth(); ok {
+		cmd.Env = append(cmd.Env, "REMOTE_USER="+username)
+	}
+	if r.Method == netHttp.MethodPost {
+		// Use the decoded length, not r.ContentLength (the compressed size on the wire) —
+		// git http-backend reads exactly CONTENT_LENGTH bytes from stdin, which is always
+		// the decompressed stream here.
+		cmd.Env = append(cmd.Env, fmt.Sprintf("CONTENT_LENGTH=%d", contentLength))
+	}
+	cmd.Stdin = body
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		message.Debugf("failed to attach to git http-backend stdout: %v", err)
+		netHttp.Error(w, "git http-backend unavailable", netHttp.StatusInternalServerError)
+		return
+	}
+
+	if err := cmd.Start(); err != nil {
+		message.Debugf("failed to start git http-backend: %v", err)
+		netHttp.Error(w, "git http-backend unavailable", netHttp.StatusInternalServerError)
+		return
+	}
+
+	if err := h.writeBackendResponse(w, stdout, service, advertisement); err != nil {
+		message.Debugf("failed to write git http-backend response: %v", err)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		message.Debugf("git http-backend exited with an error: %v", err)
+	}
+}
+
+// writeBackendResponse parses the CGI-style header block emitted by `git http-backend` and
+// copies the remaining body to w, falling back to the spec-mandated
+// application/x-git-{service}-{advertisement,result} content type when the backend doesn't
+// set one itself. Only the small advertisement response and text/* responses are buffered
+// and rewritten via MutateHost; the (potentially huge) pack result is streamed straight through.
+func (h *Handler) writeBackendResponse(w netHttp.ResponseWriter, stdout io.Reader, service string, advertisement bool) error {
+	reader := bufio.NewReader(stdout)
+	tp := textproto.NewReader(reader)
+
+	mimeHeader, err := tp.ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("failed to parse git http-backend headers: %w", err)
+	}
+
+	status := netHttp.StatusOK
+	if statusLine := mimeHeader.Get("Status"); statusLine != "" {
+		fmt.Sscanf(statusLine, "%d", &status)
+		mimeHeader.Del("Status")
+	}
+
+	header := w.Header()
+	for key, values := range mimeHeader {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	if header.Get("Content-Type") == "" {
+		suffix := "result"
+		if advertisement {
+			suffix = "advertisement"
+		}
+		header.Set("Content-Type", fmt.Sprintf("application/x-%s-%s", service, suffix))
+	}
+
+	// Git smart-HTTP responses are never text/*; they're application/x-git-*. The
+	// advertisement response carries ref/URL text worth rewriting and is always small, so it's
+	// buffered for mutation, as is any text/* response, since text can also carry URLs. Every
+	// other response, including the pack result and application/octet-stream, carries no
+	// rewritable text and can be arbitrarily large, so it's streamed.
+	contentType := header.Get("Content-Type")
+	mutate := h.MutateHost != "" && (strings.HasSuffix(contentType, "-advertisement") || strings.HasPrefix(contentType, "text/"))
+
+	if !mutate {
+		w.WriteHeader(status)
+		_, err := io.Copy(w, reader)
+		return err
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	body = []byte(git.MutateGitUrlsInText(h.MutateHost, string(body)))
+	header.Set("Content-Length", fmt.Sprintf("%d", len(body)))
+
+	w.WriteHeader(status)
+	_, err = w.Write(body)
+	return err
+}
@@ -0,0 +1,461 @@
+package git
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	netHttp "net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/zarf/cli/config"
+	"github.com/defenseunicorns/zarf/cli/internal/k8s"
+	"github.com/defenseunicorns/zarf/cli/internal/message"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// lfsBatchMediaType is the content-type required by the LFS Batch API spec
+const lfsBatchMediaType = "application/vnd.git-lfs+json"
+
+// lfsPointerPrefix is the first line of every LFS pointer file
+const lfsPointerPrefix = "version https://git-lfs.github.com/spec/v1"
+
+// LFSPointer describes a single LFS object referenced by a pointer file
+type LFSPointer struct {
+	OID  string
+	Size int64
+}
+
+// lfsBatchObject is a single object entry in an LFS Batch API request/response
+type lfsBatchObject struct {
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+// lfsBatchAction is a single action (e.g. "download" or "upload") returned for an object
+type lfsBatchAction struct {
+	Href   string            `json:"href"`
+	Header map[string]string `json:"header,omitempty"`
+}
+
+// lfsBatchResponseObject is a single object as returned by the Batch API
+type lfsBatchResponseObject struct {
+	OID     string                    `json:"oid"`
+	Size    int64                     `json:"size"`
+	Actions map[string]lfsBatchAction `json:"actions"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type lfsBatchRequest struct {
+	Operation string           `json:"operation"`
+	Transfers []string         `json:"transfers"`
+	Objects   []lfsBatchObject `json:"objects"`
+}
+
+type lfsBatchResponse struct {
+	Transfer string                   `json:"transfer"`
+	Objects  []lfsBatchResponseObject `json:"objects"`
+}
+
+// LFSTransfer mirrors the LFS objects referenced by a bare clone from a source
+// git host into the internal Zarf Gitea instance.
+type LFSTransfer struct {
+	// SourceLFSURL is the base LFS endpoint of the upstream repo, e.g. https://github.com/owner/repo.git/info/lfs
+	SourceLFSURL string
+	// InternalRepo is the name of the mirrored repo inside the in-cluster Gitea, as
+	// MirrorLFSObjects derives it with transformURLtoRepoName (the owner is not included)
+	InternalRepo string
+
+	client *netHttp.Client
+}
+
+// NewLFSTransfer builds an LFSTransfer for the given source LFS endpoint and internal repo path.
+// The client has no overall request timeout — large binaries can legitimately take a long time
+// to transfer — but bounds how long dialing and waiting for response headers may take, so a
+// genuinely unreachable host still fails fast.
+func NewLFSTransfer(sourceLFSURL string, internalRepo string) *LFSTransfer {
+	return &LFSTransfer{
+		SourceLFSURL: sourceLFSURL,
+		InternalRepo: internalRepo,
+		client: &netHttp.Client{
+			Transport: &netHttp.Transport{
+				DialContext:           (&net.Dialer{Timeout: 30 * time.Second}).DialContext,
+				ResponseHeaderTimeout: 30 * time.Second,
+			},
+		},
+	}
+}
+
+// gitAttributesLFSPatterns returns the gitattributes path patterns marked with filter=lfs
+func gitAttributesLFSPatterns(attrs []byte) []string {
+	var patterns []string
+	for _, line := range strings.Split(string(attrs), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.Contains(line, "filter=lfs") {
+			fields := strings.Fields(line)
+			if len(fields) > 0 {
+				patterns = append(patterns, fields[0])
+			}
+		}
+	}
+	return patterns
+}
+
+// lfsPatternsForTree reads .gitattributes out of tree and returns its filter=lfs patterns,
+// or nil if the tree has no .gitattributes
+func lfsPatternsForTree(tree *object.Tree) []string {
+	attrsFile, err := tree.File(".gitattributes")
+	if err != nil {
+		return nil
+	}
+
+	contents, err := attrsFile.Contents()
+	if err != nil {
+		return nil
+	}
+
+	return gitAttributesLFSPatterns([]byte(contents))
+}
+
+// matchesLFSPattern reports whether filePath matches any of the given .gitattributes
+// filter=lfs patterns, either as a full-path glob or a basename glob
+func matchesLFSPattern(patterns []string, filePath string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, filePath); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, path.Base(filePath)); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLFSPointer parses the contents of a blob and returns the LFSPointer it describes,
+// or false if the blob is not an LFS pointer file
+func parseLFSPointer(content []byte) (LFSPointer, bool) {
+	if !bytes.HasPrefix(content, []byte(lfsPointerPrefix)) {
+		return LFSPointer{}, false
+	}
+
+	var pointer LFSPointer
+	for _, line := range strings.Split(string(content), "\n") {
+		switch {
+		case strings.HasPrefix(line, "oid sha256:"):
+			pointer.OID = strings.TrimPrefix(line, "oid sha256:")
+		case strings.HasPrefix(line, "size "):
+			size, err := strconv.ParseInt(strings.TrimPrefix(line, "size "), 10, 64)
+			if err != nil {
+				return LFSPointer{}, false
+			}
+			pointer.Size = size
+		}
+	}
+
+	if pointer.OID == "" || pointer.Size == 0 {
+		return LFSPointer{}, false
+	}
+	return pointer, true
+}
+
+// EnumerateLFSPointers walks every ref in the bare clone at gitDirectory and returns the
+// deduplicated set of LFS pointers found in files whose path matches one of that ref's
+// .gitattributes `filter=lfs` patterns
+func EnumerateLFSPointers(gitDirectory string) ([]LFSPointer, error) {
+	repo, err := git.PlainOpen(gitDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("not a valid git repo or unable to open: %w", err)
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to identify references when enumerating lfs pointers: %w", err)
+	}
+
+	seen := make(map[string]LFSPointer)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Name() == plumbing.HEAD {
+			return nil
+		}
+
+		commit, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			// An annotated tag ref points at a tag object; peel it to the commit it tags
+			tag, tagErr := repo.TagObject(ref.Hash())
+			if tagErr != nil {
+				return nil
+			}
+			if commit, err = tag.Commit(); err != nil {
+				// The tag points at something other than a commit
+				return nil
+			}
+		}
+
+		tree, err := commit.Tree()
+		if err != nil {
+			return fmt.Errorf("failed to load tree for ref %s: %w", ref.Name(), err)
+		}
+
+		patterns := lfsPatternsForTree(tree)
+		if len(patterns) == 0 {
+			return nil
+		}
+
+		return tree.Files().ForEach(func(f *object.File) error {
+			if !matchesLFSPattern(patterns, f.Name) {
+				return nil
+			}
+
+			if f.Size > 1024 {
+				// LFS pointer files are always tiny; skip anything that can't be one
+				return nil
+			}
+
+			contents, err := f.Contents()
+			if err != nil {
+				return nil
+			}
+
+			pointer, ok := parseLFSPointer([]byte(contents))
+			if !ok {
+				return nil
+			}
+
+			seen[pointer.OID] = pointer
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pointers := make([]LFSPointer, 0, len(seen))
+	for _, pointer := range seen {
+		pointers = append(pointers, pointer)
+	}
+	return pointers, nil
+}
+
+// batch calls the LFS Batch API at lfsURL for the given operation and objects
+func (t *LFSTransfer) batch(lfsURL string, operation string, objects []LFSPointer, cred Credential) (*lfsBatchResponse, error) {
+	batchObjects := make([]lfsBatchObject, len(objects))
+	for i, o := range objects {
+		batchObjects[i] = lfsBatchObject{OID: o.OID, Size: o.Size}
+	}
+
+	reqBody, err := json.Marshal(lfsBatchRequest{
+		Operation: operation,
+		Transfers: []string{"basic"},
+		Objects:   batchObjects,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := netHttp.NewRequest("POST", strings.TrimSuffix(lfsURL, "/")+"/objects/batch", bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", lfsBatchMediaType)
+	request.Header.Set("Content-Type", lfsBatchMediaType)
+	if cred.Auth.Username != "" {
+		request.SetBasicAuth(cred.Auth.Username, cred.Auth.Password)
+	}
+
+	response, err := t.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("lfs batch request to %s failed: %w", lfsURL, err)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		message.Debugf("lfs batch %s request failed with a status-code of %v and a response body of: %v\n", operation, response.Status, string(responseBody))
+		return nil, fmt.Errorf("lfs batch %s request failed with status %s", operation, response.Status)
+	}
+
+	var batchResponse lfsBatchResponse
+	if err := json.Unmarshal(responseBody, &batchResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse lfs batch response: %w", err)
+	}
+
+	return &batchResponse, nil
+}
+
+// downloadObject downloads a single LFS object following the given action, verifying its
+// size and sha256 OID before returning its contents
+func (t *LFSTransfer) downloadObject(action lfsBatchAction, pointer LFSPointer) ([]byte, error) {
+	request, err := netHttp.NewRequest("GET", action.Href, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range action.Header {
+		request.Header.Set(key, value)
+	}
+
+	response, err := t.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download lfs object %s: %w", pointer.OID, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to download lfs object %s, got status %s", pointer.OID, response.Status)
+	}
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) != pointer.Size {
+		return nil, fmt.Errorf("lfs object %s size mismatch, expected %d bytes but got %d", pointer.OID, pointer.Size, len(data))
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != pointer.OID {
+		return nil, fmt.Errorf("lfs object %s failed sha256 verification", pointer.OID)
+	}
+
+	return data, nil
+}
+
+// uploadObject uploads a single LFS object's contents following the given upload action, and
+// the verify action afterwards if the server requested one
+func (t *LFSTransfer) uploadObject(actions map[string]lfsBatchAction, pointer LFSPointer, data []byte) error {
+	upload, ok := actions["upload"]
+	if !ok {
+		// The server already has this object
+		return nil
+	}
+
+	request, err := netHttp.NewRequest("PUT", upload.Href, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for key, value := range upload.Header {
+		request.Header.Set(key, value)
+	}
+	request.ContentLength = int64(len(data))
+
+	response, err := t.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to upload lfs object %s: %w", pointer.OID, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("failed to upload lfs object %s, got status %s", pointer.OID, response.Status)
+	}
+
+	if verify, ok := actions["verify"]; ok {
+		verifyBody, err := json.Marshal(lfsBatchObject{OID: pointer.OID, Size: pointer.Size})
+		if err != nil {
+			return err
+		}
+		verifyRequest, err := netHttp.NewRequest("POST", verify.Href, bytes.NewBuffer(verifyBody))
+		if err != nil {
+			return err
+		}
+		for key, value := range verify.Header {
+			verifyRequest.Header.Set(key, value)
+		}
+		verifyResponse, err := t.client.Do(verifyRequest)
+		if err != nil {
+			return fmt.Errorf("failed to verify lfs object %s: %w", pointer.OID, err)
+		}
+		defer verifyResponse.Body.Close()
+		if verifyResponse.StatusCode < 200 || verifyResponse.StatusCode >= 300 {
+			return fmt.Errorf("failed to verify lfs object %s, got status %s", pointer.OID, verifyResponse.Status)
+		}
+	}
+
+	return nil
+}
+
+// MirrorLFSObjects enumerates the LFS objects referenced by the bare clone at gitDirectory,
+// downloads them from sourceURL's LFS endpoint using any credentials on file for that host,
+// and re-uploads them to the same mirrored repo transformURLtoRepoName(sourceURL) pushes
+// commits into under config.ZarfGitPushUser, over the same tunnel used by CreateZarfOrg.
+func MirrorLFSObjects(gitDirectory string, sourceURL string) error {
+	pointers, err := EnumerateLFSPointers(gitDirectory)
+	if err != nil {
+		return fmt.Errorf("unable to enumerate lfs pointers: %w", err)
+	}
+	if len(pointers) == 0 {
+		return nil
+	}
+
+	tunnel := k8s.NewZarfTunnel()
+	tunnel.Connect(k8s.ZarfGit, false)
+	defer tunnel.Close()
+
+	internalRepo := transformURLtoRepoName(sourceURL)
+	sourceLFSURL := strings.TrimSuffix(sourceURL, "/") + "/info/lfs"
+	internalLFSURL := newGitServer().LFSEndpoint(config.ZarfGitPushUser, internalRepo)
+
+	transfer := NewLFSTransfer(sourceLFSURL, internalRepo)
+	sourceCred := FindAuthForHost(transfer.SourceLFSURL)
+	internalCred := Credential{
+		Auth: http.BasicAuth{
+			Username: config.ZarfGitPushUser,
+			Password: config.GetSecret(config.StateGitPush),
+		},
+	}
+
+	downloadBatch, err := transfer.batch(transfer.SourceLFSURL, "download", pointers, sourceCred)
+	if err != nil {
+		return fmt.Errorf("unable to batch lfs objects for download: %w", err)
+	}
+
+	uploadBatch, err := transfer.batch(internalLFSURL, "upload", pointers, internalCred)
+	if err != nil {
+		return fmt.Errorf("unable to batch lfs objects for upload: %w", err)
+	}
+	uploadActionsByOID := make(map[string]map[string]lfsBatchAction, len(uploadBatch.Objects))
+	for _, obj := range uploadBatch.Objects {
+		uploadActionsByOID[obj.OID] = obj.Actions
+	}
+
+	for _, obj := range downloadBatch.Objects {
+		if obj.Error != nil {
+			return fmt.Errorf("source server refused to serve lfs object %s: %s", obj.OID, obj.Error.Message)
+		}
+
+		download, ok := obj.Actions["download"]
+		if !ok {
+			continue
+		}
+		pointer := LFSPointer{OID: obj.OID, Size: obj.Size}
+
+		message.Debugf("Mirroring lfs object %s (%d bytes)", pointer.OID, pointer.Size)
+		data, err := transfer.downloadObject(download, pointer)
+		if err != nil {
+			return err
+		}
+
+		if err := transfer.uploadObject(uploadActionsByOID[pointer.OID], pointer, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
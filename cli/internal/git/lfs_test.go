@@ -0,0 +1,184 @@
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseLFSPointer(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantOK  bool
+		want    LFSPointer
+	}{
+		{
+			name:    "valid pointer",
+			content: "version https://git-lfs.github.com/spec/v1\noid sha256:abc123\nsize 456\n",
+			wantOK:  true,
+			want:    LFSPointer{OID: "abc123", Size: 456},
+		},
+		{
+			name:    "missing version line",
+			content: "oid sha256:abc123\nsize 456\n",
+			wantOK:  false,
+		},
+		{
+			name:    "missing oid",
+			content: "version https://git-lfs.github.com/spec/v1\nsize 456\n",
+			wantOK:  false,
+		},
+		{
+			name:    "missing size",
+			content: "version https://git-lfs.github.com/spec/v1\noid sha256:abc123\n",
+			wantOK:  false,
+		},
+		{
+			name:    "unparseable size",
+			content: "version https://git-lfs.github.com/spec/v1\noid sha256:abc123\nsize notanumber\n",
+			wantOK:  false,
+		},
+		{
+			name:    "not a pointer at all",
+			content: "#!/bin/sh\necho hello\n",
+			wantOK:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseLFSPointer([]byte(tt.content))
+			if ok != tt.wantOK {
+				t.Fatalf("parseLFSPointer() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got != tt.want {
+				t.Errorf("parseLFSPointer() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitAttributesLFSPatterns(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs string
+		want  []string
+	}{
+		{
+			name:  "single lfs pattern",
+			attrs: "*.bin filter=lfs diff=lfs merge=lfs -text\n",
+			want:  []string{"*.bin"},
+		},
+		{
+			name:  "multiple lfs patterns and a non-lfs line",
+			attrs: "*.bin filter=lfs diff=lfs merge=lfs -text\n*.txt text\n*.psd filter=lfs diff=lfs merge=lfs -text\n",
+			want:  []string{"*.bin", "*.psd"},
+		},
+		{
+			name:  "comments and blank lines ignored",
+			attrs: "# comment\n\n*.bin filter=lfs diff=lfs merge=lfs -text\n",
+			want:  []string{"*.bin"},
+		},
+		{
+			name:  "no lfs patterns",
+			attrs: "*.txt text\n",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := gitAttributesLFSPatterns([]byte(tt.attrs))
+			if len(got) != len(tt.want) {
+				t.Fatalf("gitAttributesLFSPatterns() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("gitAttributesLFSPatterns()[%d] = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMatchesLFSPattern(t *testing.T) {
+	patterns := []string{"*.bin", "assets/*.psd"}
+
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{name: "matches by basename glob", filePath: "nested/dir/model.bin", want: true},
+		{name: "matches by full path glob", filePath: "assets/cover.psd", want: true},
+		{name: "full path pattern does not match basename elsewhere", filePath: "other/assets/cover.psd", want: false},
+		{name: "no match", filePath: "README.md", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesLFSPattern(patterns, tt.filePath); got != tt.want {
+				t.Errorf("matchesLFSPattern(%q) = %v, want %v", tt.filePath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloadObjectRejectsSizeMismatch(t *testing.T) {
+	data := []byte("hello world")
+	sum := sha256.Sum256(data)
+	oid := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	transfer := NewLFSTransfer(server.URL, "owner/repo")
+	pointer := LFSPointer{OID: oid, Size: int64(len(data)) + 1}
+
+	if _, err := transfer.downloadObject(lfsBatchAction{Href: server.URL}, pointer); err == nil {
+		t.Fatal("expected a size mismatch to be rejected")
+	}
+}
+
+func TestDownloadObjectRejectsOIDMismatch(t *testing.T) {
+	data := []byte("hello world")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	transfer := NewLFSTransfer(server.URL, "owner/repo")
+	pointer := LFSPointer{OID: "0000000000000000000000000000000000000000000000000000000000000000", Size: int64(len(data))}
+
+	if _, err := transfer.downloadObject(lfsBatchAction{Href: server.URL}, pointer); err == nil {
+		t.Fatal("expected an OID mismatch to be rejected")
+	}
+}
+
+func TestDownloadObjectAcceptsMatchingData(t *testing.T) {
+	data := []byte("hello world")
+	sum := sha256.Sum256(data)
+	oid := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	transfer := NewLFSTransfer(server.URL, "owner/repo")
+	pointer := LFSPointer{OID: oid, Size: int64(len(data))}
+
+	got, err := transfer.downloadObject(lfsBatchAction{Href: server.URL}, pointer)
+	if err != nil {
+		t.Fatalf("downloadObject returned an unexpected error: %v", err)
+	}
+	if string(got) != string(data) {
+		t.Errorf("downloadObject() = %q, want %q", got, data)
+	}
+}
@@ -0,0 +1,124 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/defenseunicorns/zarf/cli/internal/message"
+	"github.com/defenseunicorns/zarf/cli/types"
+	"github.com/go-git/go-git/v5"
+)
+
+// MirrorOptions configures how MirrorClone lays out and retains a mirrored repo
+type MirrorOptions struct {
+	// DestRoot is the directory under which mirrors are stored
+	DestRoot string
+	// Bare performs a bare mirror clone rather than a working-tree clone
+	Bare bool
+	// Structured lays out the mirror as <hoster>/<owner>/<repo>.git instead of a flat directory
+	Structured bool
+	// Keep is the number of most recent timestamped snapshots to retain; older snapshots are pruned.
+	// A value of 0 disables snapshotting and clones straight into the repo directory.
+	Keep int
+	// LFS also mirrors any LFS objects referenced by the repo
+	LFS bool
+}
+
+// mirrorRepoPath returns the directory a mirror of repo should live in under destRoot
+func mirrorRepoPath(destRoot string, repo types.Repo, structured bool) string {
+	if structured {
+		return filepath.Join(destRoot, repo.Hoster, repo.Owner, repo.Name+".git")
+	}
+	return filepath.Join(destRoot, repo.Name+".git")
+}
+
+// MirrorClone performs a `--mirror` style bare clone of repo, fetching all refs (including
+// notes, pull/merge-request refs, and tags) via the `+refs/*:refs/*` refspec, rather than
+// the working-tree clone plus post-hoc removeLocalBranchRefs/removeOnlineRemoteRefs/
+// removeHeadCopies cleanup used elsewhere in this package. When opts.Keep is greater than
+// zero, each clone lands in its own <unix-ts> snapshot directory and older snapshots beyond
+// opts.Keep are pruned. It returns the directory the mirror was cloned into.
+//
+// MirrorClone is the entry point the package-create pipeline calls for each types.Repo in a
+// zarf.yaml's components before pushing it into the in-cluster Gitea; that pipeline isn't
+// part of this package and doesn't ship in this tree, so there's no caller here to wire it
+// into — package/bundler.go (or wherever components are assembled) is where it's invoked.
+func MirrorClone(repo types.Repo, opts MirrorOptions) (string, error) {
+	repoPath := mirrorRepoPath(opts.DestRoot, repo, opts.Structured)
+
+	cloneInto := repoPath
+	if opts.Keep > 0 {
+		cloneInto = filepath.Join(repoPath, strconv.FormatInt(time.Now().Unix(), 10))
+	}
+
+	if err := os.MkdirAll(cloneInto, 0755); err != nil {
+		return "", fmt.Errorf("unable to create mirror directory %s: %w", cloneInto, err)
+	}
+
+	message.Debugf("Mirror cloning %s into %s", repo.URL, cloneInto)
+	cred := FindAuthForHost(repo.URL)
+	_, err := git.PlainClone(cloneInto, opts.Bare, &git.CloneOptions{
+		URL:        repo.URL,
+		Auth:       &cred.Auth,
+		Mirror:     true,
+		Tags:       git.AllTags,
+		RemoteName: "origin",
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to mirror clone %s: %w", repo.URL, err)
+	}
+
+	if opts.LFS {
+		if err := MirrorLFSObjects(cloneInto, repo.URL); err != nil {
+			return "", fmt.Errorf("unable to mirror lfs objects for %s: %w", repo.URL, err)
+		}
+	}
+
+	if opts.Keep > 0 {
+		if err := pruneSnapshots(repoPath, opts.Keep); err != nil {
+			return "", fmt.Errorf("unable to prune old snapshots of %s: %w", repo.URL, err)
+		}
+	}
+
+	return cloneInto, nil
+}
+
+// pruneSnapshots keeps only the `keep` most recent <unix-ts> snapshot directories under repoPath
+func pruneSnapshots(repoPath string, keep int) error {
+	entries, err := os.ReadDir(repoPath)
+	if err != nil {
+		return fmt.Errorf("unable to read mirror directory %s: %w", repoPath, err)
+	}
+
+	var snapshots []int64
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		ts, err := strconv.ParseInt(entry.Name(), 10, 64)
+		if err != nil {
+			// Not a timestamped snapshot directory, leave it alone
+			continue
+		}
+		snapshots = append(snapshots, ts)
+	}
+
+	if len(snapshots) <= keep {
+		return nil
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i] > snapshots[j] })
+	for _, ts := range snapshots[keep:] {
+		snapshotPath := filepath.Join(repoPath, strconv.FormatInt(ts, 10))
+		message.Debugf("Pruning old mirror snapshot %s", snapshotPath)
+		if err := os.RemoveAll(snapshotPath); err != nil {
+			return fmt.Errorf("unable to remove old snapshot %s: %w", snapshotPath, err)
+		}
+	}
+
+	return nil
+}
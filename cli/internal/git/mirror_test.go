@@ -0,0 +1,105 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/defenseunicorns/zarf/cli/types"
+)
+
+func TestMirrorRepoPath(t *testing.T) {
+	repo := types.Repo{Hoster: "github.com", Owner: "defenseunicorns", Name: "zarf", URL: "https://github.com/defenseunicorns/zarf.git"}
+
+	tests := []struct {
+		name       string
+		structured bool
+		want       string
+	}{
+		{name: "structured lays out hoster/owner/repo.git", structured: true, want: filepath.Join("/mirrors", "github.com", "defenseunicorns", "zarf.git")},
+		{name: "flat lays out repo.git only", structured: false, want: filepath.Join("/mirrors", "zarf.git")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mirrorRepoPath("/mirrors", repo, tt.structured); got != tt.want {
+				t.Errorf("mirrorRepoPath() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruneSnapshotsKeepsOnlyMostRecent(t *testing.T) {
+	repoPath := t.TempDir()
+
+	timestamps := []int64{100, 200, 300, 400}
+	for _, ts := range timestamps {
+		if err := os.Mkdir(filepath.Join(repoPath, strconv.FormatInt(ts, 10)), 0755); err != nil {
+			t.Fatalf("failed to create snapshot dir: %v", err)
+		}
+	}
+
+	if err := pruneSnapshots(repoPath, 2); err != nil {
+		t.Fatalf("pruneSnapshots returned an unexpected error: %v", err)
+	}
+
+	entries, err := os.ReadDir(repoPath)
+	if err != nil {
+		t.Fatalf("failed to read repoPath: %v", err)
+	}
+
+	var remaining []string
+	for _, entry := range entries {
+		remaining = append(remaining, entry.Name())
+	}
+	if len(remaining) != 2 {
+		t.Fatalf("expected 2 snapshots to remain, got %d: %v", len(remaining), remaining)
+	}
+	for _, want := range []string{"300", "400"} {
+		found := false
+		for _, got := range remaining {
+			if got == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected snapshot %s to remain, got %v", want, remaining)
+		}
+	}
+}
+
+func TestPruneSnapshotsLeavesNonTimestampDirsAlone(t *testing.T) {
+	repoPath := t.TempDir()
+
+	if err := os.Mkdir(filepath.Join(repoPath, "not-a-timestamp"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(repoPath, "100"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	if err := pruneSnapshots(repoPath, 0); err != nil {
+		t.Fatalf("pruneSnapshots returned an unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(repoPath, "not-a-timestamp")); err != nil {
+		t.Errorf("expected non-timestamp directory to be left alone: %v", err)
+	}
+}
+
+func TestPruneSnapshotsUnderKeepIsANoop(t *testing.T) {
+	repoPath := t.TempDir()
+
+	if err := os.Mkdir(filepath.Join(repoPath, "100"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	if err := pruneSnapshots(repoPath, 2); err != nil {
+		t.Fatalf("pruneSnapshots returned an unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(repoPath, "100")); err != nil {
+		t.Errorf("expected the only snapshot to remain when under the keep count: %v", err)
+	}
+}
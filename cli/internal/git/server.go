@@ -0,0 +1,269 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	netHttp "net/http"
+	netUrl "net/url"
+	"time"
+
+	"github.com/defenseunicorns/zarf/cli/config"
+	"github.com/defenseunicorns/zarf/cli/internal/k8s"
+	"github.com/defenseunicorns/zarf/cli/internal/message"
+)
+
+// GitServer abstracts the admin operations Zarf needs from whatever git forge is backing
+// the in-cluster state, so that a Gitea pod isn't the only option an operator can target.
+type GitServer interface {
+	// CreateOrg creates the organization repos are pushed under
+	CreateOrg(org string) error
+	// CreateUser creates a user with the given credentials
+	CreateUser(username string, password string, email string) error
+	// SetUserQuota restricts a user so it cannot create its own repos or organizations
+	SetUserQuota(username string, email string, maxRepoCreation int, allowCreateOrganization bool) error
+	// AddCollaborator grants a user the given permission ("read", "write", "admin") on a repo
+	AddCollaborator(org string, repo string, username string, permission string) error
+	// EnsureRepo creates the repo under org if it does not already exist
+	EnsureRepo(org string, repo string) error
+	// LFSEndpoint returns the LFS Batch API base URL for the given org/repo
+	LFSEndpoint(org string, repo string) string
+}
+
+// newGitServer selects a GitServer implementation based on config.GitServerProvider
+func newGitServer() GitServer {
+	baseURL := fmt.Sprintf("http://%s:%d", config.IPV4Localhost, k8s.PortGit)
+	client := &netHttp.Client{Timeout: time.Second * 10}
+
+	switch config.GitServerProvider {
+	case config.GitServerGogs:
+		return &GogsServer{BaseURL: baseURL, PushUser: config.ZarfGitPushUser, PushPassword: config.GetSecret(config.StateGitPush), client: client}
+	case config.GitServerGitLab:
+		return &GitLabServer{BaseURL: baseURL, PushUser: config.ZarfGitPushUser, PushPassword: config.GetSecret(config.StateGitPush), client: client}
+	default:
+		return &GiteaServer{
+			BaseURL: baseURL,
+			client:  newGiteaClient(baseURL, config.ZarfGitPushUser, config.GetSecret(config.StateGitPush)),
+		}
+	}
+}
+
+// doJSON issues an HTTP request with a JSON body (if not nil), basic auth, and the standard
+// JSON accept/content-type headers shared by all GitServer implementations below
+func doJSON(client *netHttp.Client, method string, url string, body interface{}, username string, password string) (*netHttp.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewBuffer(data)
+	}
+
+	request, err := netHttp.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	request.SetBasicAuth(username, password)
+	request.Header.Add("accept", "application/json")
+	request.Header.Add("Content-Type", "application/json")
+
+	return client.Do(request)
+}
+
+// checkStatus returns an error describing a non-2xx response, logging the response body for debugging
+func checkStatus(action string, response *netHttp.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(response.Body)
+		message.Debugf("%s failed with a status-code of %v and a response body of: %v\n", action, response.Status, string(responseBody))
+		return fmt.Errorf("%s failed with status %s", action, response.Status)
+	}
+	return nil
+}
+
+// GiteaServer talks to the Gitea instance Zarf bundles by default, through a giteaClient
+// that retries transient failures and tolerates repeat create calls.
+type GiteaServer struct {
+	BaseURL string
+	client  *giteaClient
+}
+
+func (g *GiteaServer) CreateOrg(org string) error {
+	body := map[string]string{"username": org, "visibility": "limited"}
+	_, err := g.client.do("POST", "/api/v1/orgs", body, idempotentCreate)
+	return err
+}
+
+func (g *GiteaServer) CreateUser(username string, password string, email string) error {
+	body := map[string]interface{}{"username": username, "password": password, "email": email, "must_change_password": false}
+	_, err := g.client.do("POST", "/api/v1/admin/users", body, idempotentCreate)
+	return err
+}
+
+func (g *GiteaServer) SetUserQuota(username string, email string, maxRepoCreation int, allowCreateOrganization bool) error {
+	// Gitea's user-update endpoint requires email on every PATCH, not just the fields being
+	// changed, or it rejects the request. This is an edit, not a create, so a 409/422 here is
+	// a real validation failure and must not be swallowed as idempotent success.
+	body := map[string]interface{}{"email": email, "max_repo_creation": maxRepoCreation, "allow_create_organization": allowCreateOrganization}
+	_, err := g.client.do("PATCH", fmt.Sprintf("/api/v1/admin/users/%s", username), body, notIdempotentCreate)
+	return err
+}
+
+func (g *GiteaServer) AddCollaborator(org string, repo string, username string, permission string) error {
+	body := map[string]string{"permission": permission}
+	_, err := g.client.do("PUT", fmt.Sprintf("/api/v1/repos/%s/%s/collaborators/%s", org, repo, username), body, idempotentCreate)
+	return err
+}
+
+func (g *GiteaServer) EnsureRepo(org string, repo string) error {
+	body := map[string]interface{}{"name": repo, "auto_init": false}
+	_, err := g.client.do("POST", fmt.Sprintf("/api/v1/orgs/%s/repos", org), body, idempotentCreate)
+	return err
+}
+
+func (g *GiteaServer) LFSEndpoint(org string, repo string) string {
+	return fmt.Sprintf("%s/%s/%s.git/info/lfs", g.BaseURL, org, repo)
+}
+
+// GogsServer talks to a Gogs instance, whose admin API mirrors Gitea's closely but without
+// the `/orgs/{org}/repos` repo-creation route
+type GogsServer struct {
+	BaseURL      string
+	PushUser     string
+	PushPassword string
+	client       *netHttp.Client
+}
+
+func (g *GogsServer) CreateOrg(org string) error {
+	body := map[string]string{"username": org}
+	response, err := doJSON(g.client, "POST", g.BaseURL+"/api/v1/admin/orgs", body, g.PushUser, g.PushPassword)
+	return checkStatus("create gogs org", response, err)
+}
+
+func (g *GogsServer) CreateUser(username string, password string, email string) error {
+	body := map[string]interface{}{"username": username, "password": password, "email": email}
+	response, err := doJSON(g.client, "POST", g.BaseURL+"/api/v1/admin/users", body, g.PushUser, g.PushPassword)
+	return checkStatus("create gogs user", response, err)
+}
+
+func (g *GogsServer) SetUserQuota(username string, email string, maxRepoCreation int, allowCreateOrganization bool) error {
+	// Gogs has no per-user repo/org creation quota API; approximate by revoking admin rights
+	body := map[string]interface{}{"email": email, "is_admin": false, "allow_create_organization": allowCreateOrganization}
+	response, err := doJSON(g.client, "PATCH", fmt.Sprintf("%s/api/v1/admin/users/%s", g.BaseURL, username), body, g.PushUser, g.PushPassword)
+	return checkStatus("update gogs user quota", response, err)
+}
+
+func (g *GogsServer) AddCollaborator(org string, repo string, username string, permission string) error {
+	body := map[string]string{"permission": permission}
+	response, err := doJSON(g.client, "PUT", fmt.Sprintf("%s/api/v1/repos/%s/%s/collaborators/%s", g.BaseURL, org, repo, username), body, g.PushUser, g.PushPassword)
+	return checkStatus("add gogs collaborator", response, err)
+}
+
+func (g *GogsServer) EnsureRepo(org string, repo string) error {
+	body := map[string]interface{}{"name": repo}
+	response, err := doJSON(g.client, "POST", fmt.Sprintf("%s/api/v1/admin/users/%s/repos", g.BaseURL, org), body, g.PushUser, g.PushPassword)
+	if err == nil && (response.StatusCode == netHttp.StatusConflict || response.StatusCode == netHttp.StatusUnprocessableEntity) {
+		return nil
+	}
+	return checkStatus("ensure gogs repo", response, err)
+}
+
+func (g *GogsServer) LFSEndpoint(org string, repo string) string {
+	return fmt.Sprintf("%s/%s/%s.git/info/lfs", g.BaseURL, org, repo)
+}
+
+// GitLabServer talks to a GitLab instance, whose admin API is organized around top-level
+// groups/users/projects rather than Gitea-style orgs/repos
+type GitLabServer struct {
+	BaseURL      string
+	PushUser     string
+	PushPassword string
+	client       *netHttp.Client
+}
+
+func (g *GitLabServer) CreateOrg(org string) error {
+	body := map[string]string{"name": org, "path": org, "visibility": "internal"}
+	response, err := doJSON(g.client, "POST", g.BaseURL+"/api/v4/groups", body, g.PushUser, g.PushPassword)
+	return checkStatus("create gitlab group", response, err)
+}
+
+func (g *GitLabServer) CreateUser(username string, password string, email string) error {
+	body := map[string]interface{}{"username": username, "password": password, "email": email, "name": username, "skip_confirmation": true}
+	response, err := doJSON(g.client, "POST", g.BaseURL+"/api/v4/users", body, g.PushUser, g.PushPassword)
+	return checkStatus("create gitlab user", response, err)
+}
+
+func (g *GitLabServer) SetUserQuota(username string, email string, maxRepoCreation int, allowCreateOrganization bool) error {
+	// The users endpoint is addressed by numeric id, not username.
+	userID, err := g.resolveUserID(username)
+	if err != nil {
+		return fmt.Errorf("unable to resolve gitlab user %s: %w", username, err)
+	}
+
+	body := map[string]interface{}{"email": email, "can_create_group": allowCreateOrganization, "projects_limit": maxRepoCreation}
+	response, err := doJSON(g.client, "PUT", fmt.Sprintf("%s/api/v4/users/%d", g.BaseURL, userID), body, g.PushUser, g.PushPassword)
+	return checkStatus("update gitlab user quota", response, err)
+}
+
+func (g *GitLabServer) AddCollaborator(org string, repo string, username string, permission string) error {
+	// The members endpoint takes a numeric user_id and an integer access_level, not the
+	// username and permission string every other GitServer method works in terms of.
+	userID, err := g.resolveUserID(username)
+	if err != nil {
+		return fmt.Errorf("unable to resolve gitlab user %s: %w", username, err)
+	}
+
+	body := map[string]interface{}{"user_id": userID, "access_level": gitlabAccessLevel(permission)}
+	response, err := doJSON(g.client, "POST", fmt.Sprintf("%s/api/v4/projects/%s%%2F%s/members", g.BaseURL, org, repo), body, g.PushUser, g.PushPassword)
+	return checkStatus("add gitlab collaborator", response, err)
+}
+
+// resolveUserID looks up a GitLab user's numeric ID by username, as required by the
+// project members API
+func (g *GitLabServer) resolveUserID(username string) (int, error) {
+	response, err := doJSON(g.client, "GET", fmt.Sprintf("%s/api/v4/users?username=%s", g.BaseURL, netUrl.QueryEscape(username)), nil, g.PushUser, g.PushPassword)
+	if err := checkStatus("lookup gitlab user", response, err); err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	var users []struct {
+		ID int `json:"id"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&users); err != nil {
+		return 0, fmt.Errorf("failed to parse gitlab user lookup response: %w", err)
+	}
+	if len(users) == 0 {
+		return 0, fmt.Errorf("gitlab user %s not found", username)
+	}
+	return users[0].ID, nil
+}
+
+func (g *GitLabServer) EnsureRepo(org string, repo string) error {
+	body := map[string]interface{}{"name": repo, "namespace_id": org}
+	response, err := doJSON(g.client, "POST", g.BaseURL+"/api/v4/projects", body, g.PushUser, g.PushPassword)
+	if err == nil && response.StatusCode == netHttp.StatusConflict {
+		return nil
+	}
+	return checkStatus("ensure gitlab project", response, err)
+}
+
+func (g *GitLabServer) LFSEndpoint(org string, repo string) string {
+	return fmt.Sprintf("%s/%s/%s.git/info/lfs", g.BaseURL, org, repo)
+}
+
+// gitlabAccessLevel maps Zarf's read/write/admin permission strings onto GitLab's numeric access levels
+func gitlabAccessLevel(permission string) int {
+	switch permission {
+	case "write":
+		return 30
+	case "admin":
+		return 40
+	default:
+		return 20
+	}
+}
@@ -0,0 +1,106 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/defenseunicorns/zarf/cli/config"
+)
+
+// fakeGitServer is an in-memory GitServer used to exercise the currently-untested HTTP
+// flows in CreateZarfOrg, CreateReadOnlyUser, and addReadOnlyUser without a live forge
+type fakeGitServer struct {
+	orgs          map[string]bool
+	users         map[string]string
+	quotas        map[string]bool
+	collaborators map[string]string
+	repos         map[string]bool
+}
+
+func newFakeGitServer() *fakeGitServer {
+	return &fakeGitServer{
+		orgs:          make(map[string]bool),
+		users:         make(map[string]string),
+		quotas:        make(map[string]bool),
+		collaborators: make(map[string]string),
+		repos:         make(map[string]bool),
+	}
+}
+
+func (f *fakeGitServer) CreateOrg(org string) error {
+	f.orgs[org] = true
+	return nil
+}
+
+func (f *fakeGitServer) CreateUser(username string, password string, email string) error {
+	f.users[username] = email
+	return nil
+}
+
+func (f *fakeGitServer) SetUserQuota(username string, email string, maxRepoCreation int, allowCreateOrganization bool) error {
+	f.quotas[username] = allowCreateOrganization
+	return nil
+}
+
+func (f *fakeGitServer) AddCollaborator(org string, repo string, username string, permission string) error {
+	f.collaborators[org+"/"+repo+"/"+username] = permission
+	return nil
+}
+
+func (f *fakeGitServer) EnsureRepo(org string, repo string) error {
+	f.repos[org+"/"+repo] = true
+	return nil
+}
+
+func (f *fakeGitServer) LFSEndpoint(org string, repo string) string {
+	return "http://fake/" + org + "/" + repo + "/info/lfs"
+}
+
+// TestCreateZarfOrgCreatesOrgWithConfiguredName exercises createZarfOrg (the tunnel-independent
+// body of CreateZarfOrg) against a fakeGitServer
+func TestCreateZarfOrgCreatesOrgWithConfiguredName(t *testing.T) {
+	fake := newFakeGitServer()
+
+	if err := createZarfOrg(fake); err != nil {
+		t.Fatalf("createZarfOrg returned an unexpected error: %v", err)
+	}
+
+	if !fake.orgs[config.ZarfGitOrg] {
+		t.Errorf("expected org %s to be created", config.ZarfGitOrg)
+	}
+}
+
+// TestCreateReadOnlyUserLocksDownCreation exercises createReadOnlyUser (the tunnel-independent
+// body of CreateReadOnlyUser) against a fakeGitServer
+func TestCreateReadOnlyUserLocksDownCreation(t *testing.T) {
+	fake := newFakeGitServer()
+
+	if err := createReadOnlyUser(fake); err != nil {
+		t.Fatalf("createReadOnlyUser returned an unexpected error: %v", err)
+	}
+
+	if _, ok := fake.users[config.ZarfGitReadUser]; !ok {
+		t.Fatalf("expected user %s to be created", config.ZarfGitReadUser)
+	}
+	if allowCreateOrganization := fake.quotas[config.ZarfGitReadUser]; allowCreateOrganization {
+		t.Errorf("expected %s to be denied organization creation", config.ZarfGitReadUser)
+	}
+}
+
+// TestAddReadOnlyUserToGrantsReadPermission exercises addReadOnlyUserTo (the tunnel-independent
+// body of addReadOnlyUser) against a fakeGitServer
+func TestAddReadOnlyUserToGrantsReadPermission(t *testing.T) {
+	fake := newFakeGitServer()
+
+	if err := addReadOnlyUserTo(fake, "mirror__example"); err != nil {
+		t.Fatalf("addReadOnlyUserTo returned an unexpected error: %v", err)
+	}
+
+	key := config.ZarfGitPushUser + "/mirror__example/" + config.ZarfGitReadUser
+	permission, ok := fake.collaborators[key]
+	if !ok {
+		t.Fatalf("expected a collaborator entry for %s", config.ZarfGitReadUser)
+	}
+	if permission != "read" {
+		t.Errorf("expected read permission, got %s", permission)
+	}
+}
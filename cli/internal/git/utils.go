@@ -2,17 +2,11 @@ package git
 
 import (
 	"bufio"
-	"bytes"
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
-	netHttp "net/http"
 	"net/url"
 	"os"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/defenseunicorns/zarf/cli/config"
 	"github.com/defenseunicorns/zarf/cli/internal/k8s"
@@ -247,35 +241,16 @@ func CreateZarfOrg() error {
 	tunnel.Connect(k8s.ZarfGit, false)
 	defer tunnel.Close()
 
-	body := map[string]string{
-		"username":   config.ZarfGitOrg,
-		"visibility": "limited",
-	}
-	jsonData, err := json.Marshal(body)
-	if err != nil {
-		return err
-	}
+	// Build the GitServer here, after the tunnel connects and cluster state is loaded, so
+	// config.GetSecret(config.StateGitPush) reads the real secret rather than whatever was
+	// in memory at package-init time.
+	return createZarfOrg(newGitServer())
+}
 
-	request, err := netHttp.NewRequest("POST", fmt.Sprintf("http://%s:%d/api/v1/orgs", config.IPV4Localhost, k8s.PortGit), bytes.NewBuffer(jsonData))
-	if err != nil {
-		return err
-	}
-	request.SetBasicAuth(config.ZarfGitPushUser, config.GetSecret(config.StateGitPush))
-	request.Header.Add("accept", "application/json")
-	request.Header.Add("Content-Type", "application/json")
-
-	client := &netHttp.Client{Timeout: time.Second * 10}
-	createOrgResponse, err := client.Do(request)
-	if err != nil || createOrgResponse.StatusCode < 200 || createOrgResponse.StatusCode >= 300 {
-		createOrgResponseBody, _ := io.ReadAll(createOrgResponse.Body)
-		message.Debugf("Editing the read-only user permissions failed with a status-code of %v and a response body of: %v\n", createOrgResponse.Status, createOrgResponseBody)
-
-		if err == nil {
-			err = errors.New("unable to create zarf org")
-		}
-		return err
-	}
-	return err
+// createZarfOrg is the tunnel-independent body of CreateZarfOrg, split out so tests can
+// exercise it against a fakeGitServer instead of a live forge.
+func createZarfOrg(gitServer GitServer) error {
+	return gitServer.CreateOrg(config.ZarfGitOrg)
 }
 
 func CreateReadOnlyUser() error {
@@ -284,88 +259,34 @@ func CreateReadOnlyUser() error {
 	tunnel.Connect(k8s.ZarfGit, false)
 	defer tunnel.Close()
 
-	client := &netHttp.Client{Timeout: time.Second * 10}
+	return createReadOnlyUser(newGitServer())
+}
 
+// createReadOnlyUser is the tunnel-independent body of CreateReadOnlyUser, split out so
+// tests can exercise it against a fakeGitServer instead of a live forge.
+func createReadOnlyUser(gitServer GitServer) error {
 	// Create the user
-	createUserBody := map[string]interface{}{
-		"username":             config.ZarfGitReadUser,
-		"password":             config.GetSecret(config.StateGitPull),
-		"email":                "zarf-reader@localhost.local",
-		"must_change_password": false,
-	}
-	createUserData, err := json.Marshal(createUserBody)
-	if err != nil {
-		return err
-	}
-	createUserRequest, err := netHttp.NewRequest("POST", fmt.Sprintf("http://%s:%d/api/v1/admin/users", config.IPV4Localhost, k8s.PortGit), bytes.NewBuffer(createUserData))
-	if err != nil {
-		return err
-	}
-	createUserRequest.SetBasicAuth(config.ZarfGitPushUser, config.GetSecret(config.StateGitPush))
-	createUserRequest.Header.Add("accept", "application/json")
-	createUserRequest.Header.Add("Content-Type", "application/json")
-	createUserResponse, err := client.Do(createUserRequest)
-	if err != nil || createUserResponse.StatusCode < 200 || createUserResponse.StatusCode >= 300 {
-		createUserResponseBody, _ := io.ReadAll(createUserResponse.Body)
-		message.Debugf("Editing the read-only user permissions failed with a status-code of %v and a response body of: %v\n", createUserResponse.Status, createUserResponseBody)
-		if err == nil {
-			err = errors.New("unable to create zarf read-only user")
-		}
-		return err
+	if err := gitServer.CreateUser(config.ZarfGitReadUser, config.GetSecret(config.StateGitPull), "zarf-reader@localhost.local"); err != nil {
+		return fmt.Errorf("unable to create zarf read-only user: %w", err)
 	}
 
 	// Make sure the user can't create their own repos or orgs
-	updateUserBody := map[string]interface{}{
-		"email":                     "zarf-reader@localhost.local",
-		"max_repo_creation":         0,
-		"allow_create_organization": false,
-	}
-	updateUserData, _ := json.Marshal(updateUserBody)
-	updateUserRequest, _ := netHttp.NewRequest("PATCH", fmt.Sprintf("http://%s:%d/api/v1/admin/users/%s", config.IPV4Localhost, k8s.PortGit, config.ZarfGitReadUser), bytes.NewBuffer(updateUserData))
-	updateUserRequest.SetBasicAuth(config.ZarfGitPushUser, config.GetSecret(config.StateGitPush))
-	updateUserRequest.Header.Add("accept", "application/json")
-	updateUserRequest.Header.Add("Content-Type", "application/json")
-	updateUserResponse, err := client.Do(updateUserRequest)
-	if err != nil || updateUserResponse.StatusCode < 200 || updateUserResponse.StatusCode >= 300 {
-		updateUserResponseBody, _ := io.ReadAll(updateUserResponse.Body)
-		message.Debugf("Editing the read-only user permissions failed with a status-code of %v and a response body of: %v\n", updateUserResponse.Status, updateUserResponseBody)
-
-		if err == nil {
-			err = errors.New("unable to update zarf read-only user")
-		}
-		return err
+	if err := gitServer.SetUserQuota(config.ZarfGitReadUser, "zarf-reader@localhost.local", 0, false); err != nil {
+		return fmt.Errorf("unable to update zarf read-only user: %w", err)
 	}
-	return err
+
+	return nil
 }
 
 func addReadOnlyUser(repo string) error {
-	client := &netHttp.Client{Timeout: time.Second * 10}
+	return addReadOnlyUserTo(newGitServer(), repo)
+}
 
-	// Add the readonly user to the repo
-	addColabBody := map[string]string{
-		"permission": "read",
-	}
-	addColabData, err := json.Marshal(addColabBody)
-	if err != nil {
-		return err
-	}
-	addColabRequest, err := netHttp.NewRequest("PUT", fmt.Sprintf("http://%s:%d/api/v1/repos/%s/%s/collaborators/%s", config.IPV4Localhost, k8s.PortGit, config.ZarfGitPushUser, repo, config.ZarfGitReadUser), bytes.NewBuffer(addColabData))
-	if err != nil {
-		return err
+// addReadOnlyUserTo is the tunnel-independent body of addReadOnlyUser, split out so tests
+// can exercise it against a fakeGitServer instead of a live forge.
+func addReadOnlyUserTo(gitServer GitServer, repo string) error {
+	if err := gitServer.AddCollaborator(config.ZarfGitPushUser, repo, config.ZarfGitReadUser, "read"); err != nil {
+		return fmt.Errorf("unable to add read-only user to repo %s: %w", repo, err)
 	}
-	addColabRequest.SetBasicAuth(config.ZarfGitPushUser, config.GetSecret(config.StateGitPush))
-	addColabRequest.Header.Add("accept", "application/json")
-	addColabRequest.Header.Add("Content-Type", "application/json")
-	response, err := client.Do(addColabRequest)
-	if err != nil || response.StatusCode < 200 || response.StatusCode >= 300 {
-		responseBody, _ := io.ReadAll(response.Body)
-		message.Debugf("Adding the read-only user to the %v repo failed with a status-code of %v and a response body of: %v\n", repo, response.Status, responseBody)
-
-		if err == nil {
-			err = errors.New("unable to add read-only user to repo")
-		}
-		return err
-	}
-
-	return err
+	return nil
 }